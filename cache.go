@@ -0,0 +1,118 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// LookupCache is a persistent, on-disk record of resolved (and failed) name -> appid
+// lookups, so repeat or overlapping runs don't re-query Steam for names already known.
+type LookupCache struct {
+	db *sql.DB
+}
+
+// OpenCache opens (creating if necessary) a SQLite-backed cache at path.
+func OpenCache(path string) (*LookupCache, error) {
+	// busy_timeout makes SQLite block and retry internally on SQLITE_BUSY instead of
+	// failing immediately; capping the pool at one connection serializes every access
+	// through it, so concurrent workers queue rather than colliding on SQLite's
+	// single-writer lock.
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("opening cache %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+		CREATE TABLE IF NOT EXISTS lookups (
+			normalized_name TEXT PRIMARY KEY,
+			appid INTEGER,
+			resolved_at INTEGER NOT NULL,
+			ttl INTEGER NOT NULL,
+			error TEXT
+		)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating cache schema: %w", err)
+	}
+
+	return &LookupCache{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (c *LookupCache) Close() error {
+	return c.db.Close()
+}
+
+// Clear removes every cached entry.
+func (c *LookupCache) Clear() error {
+	_, err := c.db.Exec("DELETE FROM lookups")
+	return err
+}
+
+// cacheKey normalizes a game name the same way sanitizeFilename does, plus
+// lowercasing, so cache hits aren't sensitive to case or filename-unsafe characters.
+func cacheKey(gameName string) string {
+	return strings.ToLower(sanitizeFilename(gameName))
+}
+
+// cacheEntry is one resolved or failed lookup read back from the cache
+type cacheEntry struct {
+	AppID   int
+	Error   string
+	Expired bool
+}
+
+// Lookup returns the cached entry for gameName, if any. found is false when there is
+// no row at all; Expired is true when a row exists but its TTL has elapsed, in which
+// case the caller should treat it as a miss but may still want to know what it was.
+func (c *LookupCache) Lookup(gameName string) (entry cacheEntry, found bool, err error) {
+	row := c.db.QueryRow(
+		"SELECT appid, resolved_at, ttl, error FROM lookups WHERE normalized_name = ?",
+		cacheKey(gameName),
+	)
+
+	var appID sql.NullInt64
+	var resolvedAt, ttl int64
+	var cachedError sql.NullString
+	if err := row.Scan(&appID, &resolvedAt, &ttl, &cachedError); err != nil {
+		if err == sql.ErrNoRows {
+			return cacheEntry{}, false, nil
+		}
+		return cacheEntry{}, false, fmt.Errorf("reading cache entry: %w", err)
+	}
+
+	entry = cacheEntry{
+		AppID:   int(appID.Int64),
+		Error:   cachedError.String,
+		Expired: time.Now().Unix() > resolvedAt+ttl,
+	}
+	return entry, true, nil
+}
+
+// Store upserts a resolved or failed lookup with the given TTL.
+func (c *LookupCache) Store(gameName string, appID int, lookupErr error, ttl time.Duration) error {
+	errorText := ""
+	if lookupErr != nil {
+		errorText = lookupErr.Error()
+	}
+
+	_, err := c.db.Exec(
+		`INSERT INTO lookups (normalized_name, appid, resolved_at, ttl, error)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(normalized_name) DO UPDATE SET
+			appid = excluded.appid,
+			resolved_at = excluded.resolved_at,
+			ttl = excluded.ttl,
+			error = excluded.error`,
+		cacheKey(gameName), appID, time.Now().Unix(), int64(ttl.Seconds()), errorText,
+	)
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return nil
+}
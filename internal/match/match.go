@@ -0,0 +1,98 @@
+// Package match provides lightweight fuzzy string matching used to judge how
+// confident a Steam Store search result is for a given query name.
+package match
+
+import "strings"
+
+// Levenshtein returns the edit distance between a and b: the minimum number
+// of single-character insertions, deletions, or substitutions needed to turn
+// a into b.
+func Levenshtein(a, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+
+	if len(aRunes) == 0 {
+		return len(bRunes)
+	}
+	if len(bRunes) == 0 {
+		return len(aRunes)
+	}
+
+	previousRow := make([]int, len(bRunes)+1)
+	currentRow := make([]int, len(bRunes)+1)
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		currentRow[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			deletionCost := previousRow[j] + 1
+			insertionCost := currentRow[j-1] + 1
+			substitutionCost := previousRow[j-1]
+			if aRunes[i-1] != bRunes[j-1] {
+				substitutionCost++
+			}
+			currentRow[j] = min3(deletionCost, insertionCost, substitutionCost)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	return previousRow[len(bRunes)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// Ratio returns a normalized similarity score in [0, 1], where 1 means the
+// strings are identical and 0 means they share nothing. It is the
+// complement of the Levenshtein distance relative to the longer string's length.
+func Ratio(a, b string) float64 {
+	if a == "" && b == "" {
+		return 1
+	}
+	maxLen := len([]rune(a))
+	if bLen := len([]rune(b)); bLen > maxLen {
+		maxLen = bLen
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	distance := Levenshtein(a, b)
+	return 1 - float64(distance)/float64(maxLen)
+}
+
+// Normalize lowercases a name and strips everything but letters, digits, and
+// spaces, so that e.g. "DOOM (1993)" and "doom 1993" compare as close matches.
+func Normalize(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		case r == ' ':
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// BestScore scores candidate against query two ways - the raw strings, and the
+// normalized (lowercased, punctuation-stripped) variants - and returns the max,
+// since either form can be the better match depending on formatting noise.
+func BestScore(query, candidate string) float64 {
+	rawScore := Ratio(query, candidate)
+	normalizedScore := Ratio(Normalize(query), Normalize(candidate))
+	if normalizedScore > rawScore {
+		return normalizedScore
+	}
+	return rawScore
+}
@@ -0,0 +1,53 @@
+package match
+
+import "testing"
+
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "Half-Life 2", "Half-Life 2", 1},
+		{"both empty", "", "", 1},
+		{"one empty", "abc", "", 0},
+		{"completely different, same length", "abc", "xyz", 0},
+		{"single edit", "Portal", "Portl", 5.0 / 6.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Ratio(tt.a, tt.b)
+			if got != tt.want {
+				t.Errorf("Ratio(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestScore(t *testing.T) {
+	tests := []struct {
+		name             string
+		query, candidate string
+		want             float64
+	}{
+		{"exact match", "Half-Life 2", "Half-Life 2", 1},
+		{"normalization bridges punctuation", "DOOM (1993)", "doom 1993", 1},
+		{"unrelated strings score low", "Half-Life 2", "Stardew Valley", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BestScore(tt.query, tt.candidate)
+			if tt.name == "unrelated strings score low" {
+				if got >= 0.5 {
+					t.Errorf("BestScore(%q, %q) = %v, want < 0.5", tt.query, tt.candidate, got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BestScore(%q, %q) = %v, want %v", tt.query, tt.candidate, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,28 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/time/rate"
+
+	"github.com/suhailxyz/idsteamed/internal/match"
 )
 
 const (
@@ -29,28 +39,81 @@ const (
 	// Display settings
 	maxVerboseResults = 3 // Show top N results in verbose mode
 	summarySeparator  = 50
+
+	// Retry/backoff configuration
+	baseRetryDelay = 500 * time.Millisecond
+	maxRetryDelay  = 30 * time.Second
+
+	// Disambiguation settings
+	defaultScoreThreshold = 0.85 // Below this similarity, a top hit is treated as low-confidence
+	closeMatchMargin      = 0.10 // Other items within this fraction of the top score are "close"
+
+	// Cache settings
+	defaultCacheHitTTL  = 30 * 24 * time.Hour // How long a resolved appid stays valid
+	defaultCacheMissTTL = 24 * time.Hour      // How long a failed lookup is remembered, to avoid hammering the API for names that don't exist
 )
 
+// SteamSearchItem is a single entry in a Steam Store search result
+type SteamSearchItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
 // SteamAPIResponse represents the JSON response from Steam Store API
 type SteamAPIResponse struct {
-	Items []struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-	} `json:"items"`
+	Items []SteamSearchItem `json:"items"`
 }
 
 // GameResult represents the outcome of processing a single game
 type GameResult struct {
-	GameName string
-	GameID   int
-	Success  bool
-	Error    error
+	GameName     string
+	GameID       int
+	Success      bool
+	Error        error
+	RetryCount   int           // Number of retries performed before completing
+	ThrottleWait time.Duration // Time spent waiting on the rate limiter
+}
+
+// isRetryableStatus reports whether an HTTP status code warrants a retry
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
 }
 
-// findSteamGameID queries the Steam Store API and returns the game ID for the given name
-func findSteamGameID(gameName string, verbose bool) (int, error) {
+// maxBackoffShift bounds the exponent used to compute the backoff delay. Past this
+// point baseRetryDelay<<shift has long since exceeded maxRetryDelay, so clamping the
+// shift itself (rather than the resulting delay) avoids the exponent ever reaching the
+// width of the int it's shifted into, which would silently wrap the delay to 0.
+const maxBackoffShift = 16
+
+// retryDelay computes the exponential backoff delay (with jitter) for the given attempt,
+// honoring a server-provided Retry-After header when present.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	shift := attempt
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+
+	delay := baseRetryDelay * time.Duration(1<<uint(shift))
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	// Add up to 25% jitter so a thundering herd of workers doesn't retry in lockstep
+	jitter := time.Duration(rand.Int63n(int64(delay)/4 + 1))
+	return delay + jitter
+}
+
+// querySteamSearchItems queries the Steam Store API and returns every matching item.
+// Requests are paced by limiter and transient failures (network errors, 429, 5xx) are
+// retried with exponential backoff plus jitter, up to maxRetries attempts.
+func querySteamSearchItems(ctx context.Context, gameName string, limiter *rate.Limiter, maxRetries int, verbose bool) ([]SteamSearchItem, int, time.Duration, error) {
 	if gameName == "" {
-		return 0, fmt.Errorf("empty game name")
+		return nil, 0, 0, fmt.Errorf("empty game name")
 	}
 
 	// Build API request URL with query parameters
@@ -60,81 +123,235 @@ func findSteamGameID(gameName string, verbose bool) (int, error) {
 	queryParams.Set("cc", apiCountryCode)
 	requestURL := steamAPIEndpoint + "?" + queryParams.Encode()
 
-	if verbose {
-		fmt.Fprintf(os.Stderr, "  [DEBUG] Querying: %s\n", requestURL)
-	}
-
-	// Create HTTP client with timeout
 	httpClient := &http.Client{Timeout: apiTimeout}
-	requestStartTime := time.Now()
+	var throttleWait time.Duration
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		waitStart := time.Now()
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, attempt, throttleWait, fmt.Errorf("rate limiter: %w", err)
+		}
+		if waited := time.Since(waitStart); waited > time.Millisecond {
+			throttleWait += waited
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [THROTTLE] Waited %v for rate limiter\n", waited)
+			}
+		}
 
-	// Make API request
-	httpResponse, err := httpClient.Get(requestURL)
-	if err != nil {
 		if verbose {
-			fmt.Fprintf(os.Stderr, "  [ERROR] Network error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "  [DEBUG] Querying (attempt %d/%d): %s\n", attempt+1, maxRetries+1, requestURL)
 		}
-		return 0, err
-	}
-	defer httpResponse.Body.Close()
 
-	if verbose {
-		requestDuration := time.Since(requestStartTime)
-		fmt.Fprintf(os.Stderr, "  [DEBUG] Response status: %d (took %v)\n", httpResponse.StatusCode, requestDuration)
-	}
+		requestStartTime := time.Now()
+		httpResponse, err := httpClient.Get(requestURL)
+		if err != nil {
+			lastErr = err
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [ERROR] Network error: %v\n", err)
+			}
+			if attempt < maxRetries {
+				delay := retryDelay(attempt, "")
+				if verbose {
+					fmt.Fprintf(os.Stderr, "  [RETRY] Backing off %v before retry\n", delay)
+				}
+				throttleWait += delay
+				time.Sleep(delay)
+				continue
+			}
+			return nil, attempt, throttleWait, lastErr
+		}
 
-	// Validate HTTP response status
-	if httpResponse.StatusCode != http.StatusOK {
-		err := fmt.Errorf("HTTP error: %d", httpResponse.StatusCode)
 		if verbose {
-			fmt.Fprintf(os.Stderr, "  [ERROR] %v\n", err)
+			requestDuration := time.Since(requestStartTime)
+			fmt.Fprintf(os.Stderr, "  [DEBUG] Response status: %d (took %v)\n", httpResponse.StatusCode, requestDuration)
 		}
-		return 0, err
-	}
 
-	// Read response body
-	responseBody, err := io.ReadAll(httpResponse.Body)
-	if err != nil {
+		// Validate HTTP response status
+		if httpResponse.StatusCode != http.StatusOK {
+			retryAfter := httpResponse.Header.Get("Retry-After")
+			httpResponse.Body.Close()
+			lastErr = fmt.Errorf("HTTP error: %d", httpResponse.StatusCode)
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [ERROR] %v\n", lastErr)
+			}
+			if isRetryableStatus(httpResponse.StatusCode) && attempt < maxRetries {
+				delay := retryDelay(attempt, retryAfter)
+				if verbose {
+					fmt.Fprintf(os.Stderr, "  [RETRY] Backing off %v before retry\n", delay)
+				}
+				throttleWait += delay
+				time.Sleep(delay)
+				continue
+			}
+			return nil, attempt, throttleWait, lastErr
+		}
+
+		// Read response body
+		responseBody, err := io.ReadAll(httpResponse.Body)
+		httpResponse.Body.Close()
+		if err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [ERROR] Failed to read response: %v\n", err)
+			}
+			return nil, attempt, throttleWait, err
+		}
+
+		// Parse JSON response
+		var apiResponse SteamAPIResponse
+		if err := json.Unmarshal(responseBody, &apiResponse); err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [ERROR] JSON parse error: %v\n", err)
+			}
+			return nil, attempt, throttleWait, err
+		}
+
+		// Log search results in verbose mode
 		if verbose {
-			fmt.Fprintf(os.Stderr, "  [ERROR] Failed to read response: %v\n", err)
+			resultCount := len(apiResponse.Items)
+			fmt.Fprintf(os.Stderr, "  [DEBUG] Found %d result(s)\n", resultCount)
+			if resultCount > 0 {
+				// Show top results for debugging
+				for i, item := range apiResponse.Items {
+					if i < maxVerboseResults {
+						fmt.Fprintf(os.Stderr, "    %d. %s (ID: %d)\n", i+1, item.Name, item.ID)
+					}
+				}
+			}
+		}
+
+		if len(apiResponse.Items) == 0 {
+			err = fmt.Errorf("no results found")
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [ERROR] %v\n", err)
+			}
+			return nil, attempt, throttleWait, err
 		}
-		return 0, err
+
+		return apiResponse.Items, attempt, throttleWait, nil
 	}
 
-	// Parse JSON response
-	var apiResponse SteamAPIResponse
-	if err := json.Unmarshal(responseBody, &apiResponse); err != nil {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "  [ERROR] JSON parse error: %v\n", err)
+	return nil, maxRetries, throttleWait, lastErr
+}
+
+// scoredItem pairs a search result with its fuzzy-match confidence against the query
+type scoredItem struct {
+	item  SteamSearchItem
+	score float64
+}
+
+// scoreSearchItems ranks items by similarity to gameName, highest score first
+func scoreSearchItems(gameName string, items []SteamSearchItem) []scoredItem {
+	scored := make([]scoredItem, len(items))
+	for i, item := range items {
+		scored[i] = scoredItem{item: item, score: match.BestScore(gameName, item.Name)}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+	return scored
+}
+
+// isAmbiguous reports whether the top-scoring item is not a confident, unique match:
+// either its score falls below the threshold, or another item scores close behind it.
+func isAmbiguous(scored []scoredItem, scoreThreshold float64) bool {
+	if len(scored) == 0 {
+		return false
+	}
+	topScore := scored[0].score
+	if topScore < scoreThreshold {
+		return true
+	}
+	for _, candidate := range scored[1:] {
+		if candidate.score >= topScore*(1-closeMatchMargin) {
+			return true
+		}
+	}
+	return false
+}
+
+// stdinPrompter serializes interactive disambiguation prompts across concurrent
+// workers and shares a single buffered stdin reader between them. A fresh
+// bufio.Reader per prompt would each buffer ahead past their own line, silently
+// dropping any input a user (or piped script) answered for a later prompt.
+type stdinPrompter struct {
+	mu     sync.Mutex
+	reader *bufio.Reader
+}
+
+func newStdinPrompter() *stdinPrompter {
+	return &stdinPrompter{reader: bufio.NewReader(os.Stdin)}
+}
+
+// promptForSelection prints a numbered list of candidates to stderr and blocks on stdin
+// for the user's choice. prompter serializes prompts so concurrent workers don't
+// interleave output. Accepts a list index, "s" to skip, or a literal appid.
+func promptForSelection(gameName string, scored []scoredItem, prompter *stdinPrompter) (int, error) {
+	prompter.mu.Lock()
+	defer prompter.mu.Unlock()
+
+	fmt.Fprintf(os.Stderr, "\nAmbiguous match for %q:\n", gameName)
+	for i, candidate := range scored {
+		if i >= maxVerboseResults*2 {
+			break
 		}
-		return 0, err
+		fmt.Fprintf(os.Stderr, "  %d. %s (ID: %d, score: %.2f)\n", i+1, candidate.item.Name, candidate.item.ID, candidate.score)
 	}
+	fmt.Fprintf(os.Stderr, "Pick a number, enter a custom appid, or 's' to skip: ")
 
-	// Log search results in verbose mode
-	if verbose {
-		resultCount := len(apiResponse.Items)
-		fmt.Fprintf(os.Stderr, "  [DEBUG] Found %d result(s)\n", resultCount)
-		if resultCount > 0 {
-			// Show top results for debugging
-			for i, item := range apiResponse.Items {
-				if i < maxVerboseResults {
-					fmt.Fprintf(os.Stderr, "    %d. %s (ID: %d)\n", i+1, item.Name, item.ID)
-				}
-			}
+	line, err := prompter.reader.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("reading selection: %w", err)
+	}
+	choice := strings.TrimSpace(line)
+
+	if strings.EqualFold(choice, "s") {
+		return 0, fmt.Errorf("skipped by user")
+	}
+
+	if choiceNum, err := strconv.Atoi(choice); err == nil {
+		if choiceNum >= 1 && choiceNum <= len(scored) {
+			return scored[choiceNum-1].item.ID, nil
 		}
+		// Not a valid list index - treat it as a literal appid the user typed directly
+		return choiceNum, nil
 	}
 
-	// Return the top-ranked result (Steam's API returns best match first)
-	if len(apiResponse.Items) > 0 {
-		return apiResponse.Items[0].ID, nil
+	return 0, fmt.Errorf("invalid selection %q", choice)
+}
+
+// selectSteamGameID picks the best search result for gameName, falling back to an
+// interactive prompt when the top hit is ambiguous and interactive mode is enabled,
+// or failing the lookup outright when scoreThreshold rejects a low-confidence match.
+func selectSteamGameID(gameName string, items []SteamSearchItem, interactive bool, scoreThreshold float64, prompter *stdinPrompter, verbose bool) (int, error) {
+	scored := scoreSearchItems(gameName, items)
+
+	if interactive && isAmbiguous(scored, defaultScoreThreshold) {
+		return promptForSelection(gameName, scored, prompter)
+	}
+
+	topScore := scored[0].score
+	if scoreThreshold > 0 && topScore < scoreThreshold {
+		return 0, fmt.Errorf("low-confidence match: top score %.2f below threshold %.2f", topScore, scoreThreshold)
 	}
 
-	// No results found
-	err = fmt.Errorf("no results found")
 	if verbose {
-		fmt.Fprintf(os.Stderr, "  [ERROR] %v\n", err)
+		fmt.Fprintf(os.Stderr, "  [DEBUG] Selected %q (score: %.2f)\n", scored[0].item.Name, topScore)
 	}
-	return 0, err
+
+	return scored[0].item.ID, nil
+}
+
+// findSteamGameID queries the Steam Store API and resolves the game ID for the given
+// name, disambiguating between multiple candidates via selectSteamGameID.
+func findSteamGameID(ctx context.Context, gameName string, limiter *rate.Limiter, maxRetries int, interactive bool, scoreThreshold float64, prompter *stdinPrompter, verbose bool) (int, int, time.Duration, error) {
+	items, retryCount, throttleWait, err := querySteamSearchItems(ctx, gameName, limiter, maxRetries, verbose)
+	if err != nil {
+		return 0, retryCount, throttleWait, err
+	}
+
+	gameID, err := selectSteamGameID(gameName, items, interactive, scoreThreshold, prompter, verbose)
+	return gameID, retryCount, throttleWait, err
 }
 
 // sanitizeFilename converts a game name into a safe filename by removing invalid characters
@@ -163,12 +380,74 @@ func sanitizeFilename(gameName string) string {
 	return cleaned
 }
 
-// processSingleGame handles one game: looks up ID and writes .steam file
-func processSingleGame(gameName string, outputDirectory string, shouldSkipExisting bool, verbose bool) GameResult {
+// lookupOptions bundles the tunables processSingleGame needs to resolve a game ID,
+// separate from the per-game values (name, output dir) so the parameter list doesn't
+// keep growing as more lookup strategies are added.
+type lookupOptions struct {
+	limiter        *rate.Limiter
+	maxRetries     int
+	knownAppIDs    map[string]int
+	interactive    bool
+	scoreThreshold float64
+	prompter       *stdinPrompter
+	cache          *LookupCache
+	cacheHitTTL    time.Duration
+	refresh        bool
+}
+
+// processSingleGame handles one game: looks up ID and writes .steam file.
+// If knownAppIDs already has a locally-scanned appid for this name, the Steam
+// Store lookup is skipped entirely.
+func processSingleGame(ctx context.Context, gameName string, outputDirectory string, shouldSkipExisting bool, verbose bool, opts lookupOptions) GameResult {
 	// Generate output file path
 	sanitizedFilename := sanitizeFilename(gameName)
 	steamFilePath := filepath.Join(outputDirectory, sanitizedFilename+".steam")
 
+	// A local Steam library scan already told us the appid - no need to query the API
+	if appID, found := opts.knownAppIDs[normalizeGameName(gameName)]; found {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "  [LOCAL] Resolved from Steam library scan: %s -> %d\n", gameName, appID)
+		}
+		gameIDString := fmt.Sprintf("%d", appID)
+		if err := os.WriteFile(steamFilePath, []byte(gameIDString), fileMode); err != nil {
+			return GameResult{
+				GameName: gameName,
+				Success:  false,
+				Error:    fmt.Errorf("error writing file: %v", err),
+			}
+		}
+		return GameResult{
+			GameName: gameName,
+			GameID:   appID,
+			Success:  true,
+		}
+	}
+
+	// Consult the persistent cache before the Steam API, unless a refresh was requested
+	if opts.cache != nil && !opts.refresh {
+		if entry, found, err := opts.cache.Lookup(gameName); err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [WARN] Cache lookup failed for %s: %v\n", gameName, err)
+			}
+		} else if found && !entry.Expired {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "  [CACHE] Hit for %s\n", gameName)
+			}
+			if entry.Error != "" {
+				return GameResult{GameName: gameName, Success: false, Error: fmt.Errorf("%s", entry.Error)}
+			}
+			gameIDString := fmt.Sprintf("%d", entry.AppID)
+			if err := os.WriteFile(steamFilePath, []byte(gameIDString), fileMode); err != nil {
+				return GameResult{
+					GameName: gameName,
+					Success:  false,
+					Error:    fmt.Errorf("error writing file: %v", err),
+				}
+			}
+			return GameResult{GameName: gameName, GameID: entry.AppID, Success: true}
+		}
+	}
+
 	// Skip existing files if requested
 	if shouldSkipExisting {
 		if _, err := os.Stat(steamFilePath); err == nil {
@@ -191,12 +470,19 @@ func processSingleGame(gameName string, outputDirectory string, shouldSkipExisti
 	}
 
 	// Query Steam API for game ID
-	gameID, err := findSteamGameID(gameName, verbose)
+	gameID, retryCount, throttleWait, err := findSteamGameID(ctx, gameName, opts.limiter, opts.maxRetries, opts.interactive, opts.scoreThreshold, opts.prompter, verbose)
 	if err != nil {
+		if opts.cache != nil {
+			if cacheErr := opts.cache.Store(gameName, 0, err, defaultCacheMissTTL); cacheErr != nil {
+				fmt.Fprintf(os.Stderr, "  [WARN] Could not cache failed lookup for %s: %v\n", gameName, cacheErr)
+			}
+		}
 		return GameResult{
-			GameName: gameName,
-			Success:  false,
-			Error:    err,
+			GameName:     gameName,
+			Success:      false,
+			Error:        err,
+			RetryCount:   retryCount,
+			ThrottleWait: throttleWait,
 		}
 	}
 
@@ -204,24 +490,38 @@ func processSingleGame(gameName string, outputDirectory string, shouldSkipExisti
 	gameIDString := fmt.Sprintf("%d", gameID)
 	if err := os.WriteFile(steamFilePath, []byte(gameIDString), fileMode); err != nil {
 		return GameResult{
-			GameName: gameName,
-			Success:  false,
-			Error:    fmt.Errorf("error writing file: %v", err),
+			GameName:     gameName,
+			Success:      false,
+			Error:        fmt.Errorf("error writing file: %v", err),
+			RetryCount:   retryCount,
+			ThrottleWait: throttleWait,
+		}
+	}
+
+	if opts.cache != nil {
+		hitTTL := opts.cacheHitTTL
+		if hitTTL <= 0 {
+			hitTTL = defaultCacheHitTTL
+		}
+		if cacheErr := opts.cache.Store(gameName, gameID, nil, hitTTL); cacheErr != nil {
+			fmt.Fprintf(os.Stderr, "  [WARN] Could not cache resolved lookup for %s: %v\n", gameName, cacheErr)
 		}
 	}
 
 	return GameResult{
-		GameName: gameName,
-		GameID:   gameID,
-		Success:  true,
+		GameName:     gameName,
+		GameID:       gameID,
+		Success:      true,
+		RetryCount:   retryCount,
+		ThrottleWait: throttleWait,
 	}
 }
 
 // workerGoroutine processes games from the jobs channel and sends results back
-func workerGoroutine(jobQueue <-chan string, resultQueue chan<- GameResult, outputDirectory string, shouldSkipExisting bool, verbose bool, waitGroup *sync.WaitGroup) {
+func workerGoroutine(ctx context.Context, jobQueue <-chan string, resultQueue chan<- GameResult, outputDirectory string, shouldSkipExisting bool, verbose bool, opts lookupOptions, waitGroup *sync.WaitGroup) {
 	defer waitGroup.Done()
 	for gameName := range jobQueue {
-		resultQueue <- processSingleGame(gameName, outputDirectory, shouldSkipExisting, verbose)
+		resultQueue <- processSingleGame(ctx, gameName, outputDirectory, shouldSkipExisting, verbose, opts)
 	}
 }
 
@@ -231,71 +531,216 @@ func main() {
 	workerCountFlag := flag.Int("workers", 8, "Number of concurrent workers")
 	skipExistingFlag := flag.Bool("skip-existing", false, "Skip games that already have .steam files")
 	verboseFlag := flag.Bool("verbose", false, "Show detailed output")
+	rateFlag := flag.Float64("rate", 5, "Maximum Steam API requests per second")
+	burstFlag := flag.Int("burst", 5, "Burst size allowed by the rate limiter")
+	maxRetriesFlag := flag.Int("max-retries", 4, "Maximum retries for transient API failures")
+	steamDirFlag := flag.String("steam-dir", "", "Path to a local Steam install to scan for games (use \"auto\" to auto-detect)")
+	interactiveFlag := flag.Bool("interactive", false, "Prompt to disambiguate low-confidence or close-scoring matches")
+	scoreThresholdFlag := flag.Float64("score-threshold", 0, "Reject non-interactive matches scoring below this (0-1, 0 disables)")
+	serveFlag := flag.String("serve", "", "Run an HTTP daemon on this address (e.g. :8080) instead of a one-shot batch run")
+	quietFlag := flag.Bool("quiet", false, "Suppress per-game output, printing only a final summary")
+	outputFormatValue := flag.String("output-format", string(outputFormatText), "Output format: text, json, or ndjson")
+	cacheFlag := flag.String("cache", "", "Path to a SQLite cache of resolved appids (persists across runs)")
+	cacheTTLFlag := flag.Duration("cache-ttl", defaultCacheHitTTL, "How long a cached appid stays valid")
+	cacheClearFlag := flag.Bool("cache-clear", false, "Clear the --cache database and exit")
+	refreshFlag := flag.Bool("refresh", false, "Bypass cached results, re-querying the API and writing fresh results back to the cache")
 
 	// Custom help message
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <input_file.txt>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [input_file.txt]\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExample:\n")
 		fmt.Fprintf(os.Stderr, "  %s games.txt\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --output my_output --workers 16 --skip-existing games.txt\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --verbose games.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --steam-dir auto\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --steam-dir auto games.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --interactive games.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --serve :8080\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --output-format ndjson games.txt | jq .\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --cache cache.db games.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --cache cache.db --cache-clear\n", os.Args[0])
 	}
 
 	flag.Parse()
 
-	// Validate input file argument
-	commandLineArgs := flag.Args()
-	if len(commandLineArgs) < 1 {
-		flag.Usage()
+	outputFormatFlag, err := parseOutputFormat(*outputFormatValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	inputFilePath := commandLineArgs[0]
 
-	// Verify input file exists
-	if _, err := os.Stat(inputFilePath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: File '%s' not found.\n", inputFilePath)
+	// Disable color globally rather than relying on fatih/color's own (version-dependent)
+	// detection, so both TTY and NO_COLOR are handled the same way everywhere
+	color.NoColor = !isOutputTerminal() || os.Getenv("NO_COLOR") != ""
+
+	// Open the persistent appid cache, if requested. --cache-clear wipes it and exits
+	// immediately, mirroring how --serve exits the normal batch flow.
+	var cache *LookupCache
+	if *cacheFlag != "" {
+		openedCache, err := OpenCache(*cacheFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening cache '%s': %v\n", *cacheFlag, err)
+			os.Exit(1)
+		}
+		defer openedCache.Close()
+		cache = openedCache
+
+		if *cacheClearFlag {
+			if err := cache.Clear(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error clearing cache '%s': %v\n", *cacheFlag, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Cleared cache '%s'\n", *cacheFlag)
+			return
+		}
+	} else if *cacheClearFlag {
+		fmt.Fprintf(os.Stderr, "Error: --cache-clear requires --cache\n")
 		os.Exit(1)
 	}
 
-	// Create output directory
-	if err := os.MkdirAll(*outputDirectoryFlag, dirMode); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
-		os.Exit(1)
+	// Daemon mode: serve lookups over HTTP instead of processing a batch and exiting
+	if *serveFlag != "" {
+		if err := os.MkdirAll(*outputDirectoryFlag, dirMode); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		limiter := rate.NewLimiter(rate.Limit(*rateFlag), *burstFlag)
+		opts := lookupOptions{
+			limiter:        limiter,
+			maxRetries:     *maxRetriesFlag,
+			knownAppIDs:    map[string]int{},
+			scoreThreshold: *scoreThresholdFlag,
+			cache:          cache,
+			cacheHitTTL:    *cacheTTLFlag,
+			refresh:        *refreshFlag,
+		}
+
+		if err := runServer(*serveFlag, *outputDirectoryFlag, opts, *verboseFlag, *workerCountFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Read and parse input file
-	inputFileContent, err := os.ReadFile(inputFilePath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
-		os.Exit(1)
+	// Resolve the local Steam library, if requested. Its manifests give us an
+	// appid -> no Steam Store lookup required for any matching name.
+	knownAppIDs := map[string]int{}
+	var scannedGameNames []string
+	if *steamDirFlag != "" {
+		steamDir := *steamDirFlag
+		if steamDir == "auto" {
+			detectedDir, err := detectSteamDir()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			steamDir = detectedDir
+		}
+
+		installedGames, err := scanInstalledGames(steamDir, *verboseFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning Steam directory '%s': %v\n", steamDir, err)
+			os.Exit(1)
+		}
+
+		for _, game := range installedGames {
+			knownAppIDs[normalizeGameName(game.Name)] = game.AppID
+			scannedGameNames = append(scannedGameNames, game.Name)
+		}
+		fmt.Printf("Discovered %d installed game(s) in %s\n", len(installedGames), steamDir)
 	}
 
-	// Extract game names from file (one per line, skip empty lines)
-	inputLines := strings.Split(string(inputFileContent), "\n")
+	// Validate input file argument - optional when a Steam directory scan already
+	// produced a list of games to process
+	commandLineArgs := flag.Args()
 	var gameNames []string
-	for _, line := range inputLines {
-		trimmedLine := strings.TrimSpace(line)
-		if trimmedLine != "" {
-			gameNames = append(gameNames, trimmedLine)
+	switch {
+	case len(commandLineArgs) >= 1:
+		inputFilePath := commandLineArgs[0]
+
+		// Verify input file exists
+		if _, err := os.Stat(inputFilePath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: File '%s' not found.\n", inputFilePath)
+			os.Exit(1)
 		}
+
+		// Read and parse input file
+		inputFileContent, err := os.ReadFile(inputFilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Extract game names from file (one per line, skip empty lines)
+		inputLines := strings.Split(string(inputFileContent), "\n")
+		for _, line := range inputLines {
+			trimmedLine := strings.TrimSpace(line)
+			if trimmedLine != "" {
+				gameNames = append(gameNames, trimmedLine)
+			}
+		}
+	case len(scannedGameNames) > 0:
+		// No input file - the Steam directory scan is the only source of names
+		gameNames = scannedGameNames
+	default:
+		flag.Usage()
+		os.Exit(1)
 	}
 
 	if len(gameNames) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: No game names found in file.\n")
+		fmt.Fprintf(os.Stderr, "Error: No game names found.\n")
 		os.Exit(1)
 	}
 
-	// Display processing info
+	// Create output directory
+	if err := os.MkdirAll(*outputDirectoryFlag, dirMode); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Display processing info. In json/ndjson mode this goes to stderr so stdout stays
+	// clean, machine-parseable output.
 	totalGames := len(gameNames)
-	fmt.Printf("Processing %d game(s)...\n", totalGames)
-	if *verboseFlag {
-		fmt.Printf("  Output directory: %s\n", *outputDirectoryFlag)
-		fmt.Printf("  Workers: %d\n", *workerCountFlag)
-		fmt.Printf("  Skip existing: %v\n", *skipExistingFlag)
+	infoWriter := os.Stdout
+	if outputFormatFlag != outputFormatText {
+		infoWriter = os.Stderr
+	}
+	if !*quietFlag {
+		fmt.Fprintf(infoWriter, "Processing %d game(s)...\n", totalGames)
+		if *verboseFlag {
+			fmt.Fprintf(infoWriter, "  Output directory: %s\n", *outputDirectoryFlag)
+			fmt.Fprintf(infoWriter, "  Workers: %d\n", *workerCountFlag)
+			fmt.Fprintf(infoWriter, "  Skip existing: %v\n", *skipExistingFlag)
+			fmt.Fprintf(infoWriter, "  Rate limit: %.2f req/s (burst %d)\n", *rateFlag, *burstFlag)
+			fmt.Fprintf(infoWriter, "  Max retries: %d\n", *maxRetriesFlag)
+			fmt.Fprintf(infoWriter, "  Interactive: %v\n", *interactiveFlag)
+			if *scoreThresholdFlag > 0 {
+				fmt.Fprintf(infoWriter, "  Score threshold: %.2f\n", *scoreThresholdFlag)
+			}
+			if *cacheFlag != "" {
+				fmt.Fprintf(infoWriter, "  Cache: %s (ttl %v, refresh %v)\n", *cacheFlag, *cacheTTLFlag, *refreshFlag)
+			}
+		}
+		fmt.Fprintln(infoWriter)
+	}
+
+	ctx := context.Background()
+	limiter := rate.NewLimiter(rate.Limit(*rateFlag), *burstFlag)
+	prompter := newStdinPrompter()
+	opts := lookupOptions{
+		limiter:        limiter,
+		maxRetries:     *maxRetriesFlag,
+		knownAppIDs:    knownAppIDs,
+		interactive:    *interactiveFlag,
+		scoreThreshold: *scoreThresholdFlag,
+		prompter:       prompter,
+		cache:          cache,
+		cacheHitTTL:    *cacheTTLFlag,
+		refresh:        *refreshFlag,
 	}
-	fmt.Println()
 
 	// Adjust worker count if we have fewer games than workers
 	actualWorkerCount := *workerCountFlag
@@ -311,7 +756,7 @@ func main() {
 	var workerWaitGroup sync.WaitGroup
 	for i := 0; i < actualWorkerCount; i++ {
 		workerWaitGroup.Add(1)
-		go workerGoroutine(jobQueue, resultQueue, *outputDirectoryFlag, *skipExistingFlag, *verboseFlag, &workerWaitGroup)
+		go workerGoroutine(ctx, jobQueue, resultQueue, *outputDirectoryFlag, *skipExistingFlag, *verboseFlag, opts, &workerWaitGroup)
 	}
 
 	// Send all game names to job queue
@@ -333,38 +778,88 @@ func main() {
 	failedCount := 0
 	var failedGameNames []string
 	completedCount := 0
+	totalRetries := 0
+	var totalThrottleWait time.Duration
+	var allResults []GameResult
+
+	useProgressBar := outputFormatFlag == outputFormatText && !*quietFlag && isOutputTerminal()
+	var bar *progressBar
+	if useProgressBar {
+		bar = newProgressBar(totalGames)
+	}
 
 	for result := range resultQueue {
 		completedCount++
+		totalRetries += result.RetryCount
+		totalThrottleWait += result.ThrottleWait
 
 		if result.Success {
-			fmt.Printf("[%d/%d] %s... ✓ Found (ID: %d)\n", completedCount, totalGames, result.GameName, result.GameID)
 			successCount++
 		} else {
-			// Show error message in verbose mode, otherwise generic "Not found"
-			errorMessage := "Not found"
-			if *verboseFlag && result.Error != nil {
-				errorMessage = result.Error.Error()
-			}
-			fmt.Printf("[%d/%d] %s... ✗ %s\n", completedCount, totalGames, result.GameName, errorMessage)
 			failedCount++
 			failedGameNames = append(failedGameNames, result.GameName)
 		}
+
+		switch {
+		case outputFormatFlag == outputFormatNDJSON:
+			writeNDJSONLine(newNDJSONResult(result))
+		case outputFormatFlag == outputFormatJSON:
+			allResults = append(allResults, result)
+		case useProgressBar:
+			bar.render(completedCount, successCount, failedCount)
+		case !*quietFlag:
+			fmt.Println(formatResultLine(result, completedCount, totalGames, *verboseFlag))
+		}
 	}
 
-	// Print summary
-	fmt.Printf("\n%s\n", strings.Repeat("=", summarySeparator))
-	fmt.Println("Summary:")
-	fmt.Printf("  Success: %d\n", successCount)
-	fmt.Printf("  Failed:  %d\n", failedCount)
-	fmt.Printf("  Output:  %s/\n", *outputDirectoryFlag)
+	if useProgressBar {
+		bar.finish()
+	}
 
-	if len(failedGameNames) > 0 {
-		fmt.Println("\nFailed games:")
-		for _, gameName := range failedGameNames {
-			fmt.Printf("  - %s\n", gameName)
-		}
+	summary := runSummary{
+		Success:     successCount,
+		Failed:      failedCount,
+		Output:      *outputDirectoryFlag,
+		FailedGames: failedGameNames,
+	}
+	if totalRetries > 0 || totalThrottleWait > 0 {
+		summary.Retries = totalRetries
+		summary.ThrottledFor = totalThrottleWait.String()
 	}
 
-	fmt.Printf("\nDone! Check the '%s/' folder for .steam files.\n", *outputDirectoryFlag)
+	switch outputFormatFlag {
+	case outputFormatNDJSON:
+		writeNDJSONLine(struct {
+			Summary runSummary `json:"summary"`
+		}{Summary: summary})
+	case outputFormatJSON:
+		json.NewEncoder(os.Stdout).Encode(struct {
+			Results []GameResult `json:"results"`
+			Summary runSummary   `json:"summary"`
+		}{Results: allResults, Summary: summary})
+	default:
+		if *quietFlag {
+			fmt.Printf("Success: %d  Failed: %d  Output: %s/\n", successCount, failedCount, *outputDirectoryFlag)
+			break
+		}
+
+		fmt.Printf("\n%s\n", strings.Repeat("=", summarySeparator))
+		fmt.Println("Summary:")
+		fmt.Printf("  Success: %d\n", successCount)
+		fmt.Printf("  Failed:  %d\n", failedCount)
+		fmt.Printf("  Output:  %s/\n", *outputDirectoryFlag)
+		if *verboseFlag && (totalRetries > 0 || totalThrottleWait > 0) {
+			fmt.Printf("  Retries: %d\n", totalRetries)
+			fmt.Printf("  Throttled time: %v\n", totalThrottleWait)
+		}
+
+		if len(failedGameNames) > 0 {
+			fmt.Println("\nFailed games:")
+			for _, gameName := range failedGameNames {
+				fmt.Printf("  - %s\n", gameName)
+			}
+		}
+
+		fmt.Printf("\nDone! Check the '%s/' folder for .steam files.\n", *outputDirectoryFlag)
+	}
 }
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestRetryDelayClamping(t *testing.T) {
+	// These previously overflowed the shift once attempt reached the width of the
+	// int it was shifted into, silently wrapping the delay to 0 and panicking in
+	// rand.Int63n. None of these should panic, and all should land near maxRetryDelay.
+	attempts := []int{0, 1, 5, 10, maxBackoffShift, maxBackoffShift + 1, 63, 64, 65, 1000}
+
+	for _, attempt := range attempts {
+		delay := retryDelay(attempt, "")
+		if delay <= 0 {
+			t.Errorf("retryDelay(%d, \"\") = %v, want > 0", attempt, delay)
+		}
+		if delay > maxRetryDelay+maxRetryDelay/4+1 {
+			t.Errorf("retryDelay(%d, \"\") = %v, want <= maxRetryDelay plus jitter", attempt, delay)
+		}
+	}
+}
+
+func TestRetryDelayHonorsRetryAfter(t *testing.T) {
+	delay := retryDelay(0, "7")
+	if delay.Seconds() != 7 {
+		t.Errorf("retryDelay(0, \"7\") = %v, want 7s", delay)
+	}
+}
+
+func TestRetryDelayIncreasesWithAttempt(t *testing.T) {
+	early := retryDelay(0, "")
+	late := retryDelay(5, "")
+	if late < early {
+		t.Errorf("retryDelay(5, \"\") = %v, want >= retryDelay(0, \"\") = %v", late, early)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// outputFormat selects how per-game results and the final summary are rendered
+type outputFormat string
+
+const (
+	outputFormatText   outputFormat = "text"
+	outputFormatJSON   outputFormat = "json"
+	outputFormatNDJSON outputFormat = "ndjson"
+
+	progressBarWidth = 20
+)
+
+// parseOutputFormat validates a --output-format value
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch outputFormat(value) {
+	case outputFormatText, outputFormatJSON, outputFormatNDJSON:
+		return outputFormat(value), nil
+	default:
+		return "", fmt.Errorf("invalid --output-format %q (want text, json, or ndjson)", value)
+	}
+}
+
+// isOutputTerminal reports whether stdout is an interactive terminal, the condition
+// under which the progress bar should be drawn instead of a static transcript.
+func isOutputTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+var (
+	successColor = color.New(color.FgGreen)
+	failureColor = color.New(color.FgRed)
+)
+
+// progressBar renders a single-line, in-place progress indicator like:
+//
+//	[==========>          ] 42/100  ok=40 fail=2  eta=15s
+type progressBar struct {
+	total     int
+	startTime time.Time
+}
+
+func newProgressBar(total int) *progressBar {
+	return &progressBar{total: total, startTime: time.Now()}
+}
+
+// render draws the current state, overwriting the previous line
+func (p *progressBar) render(completed, succeeded, failed int) {
+	fraction := 0.0
+	if p.total > 0 {
+		fraction = float64(completed) / float64(p.total)
+	}
+	filled := int(fraction * progressBarWidth)
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	eta := estimateETA(p.startTime, completed, p.total)
+	fmt.Printf("\r[%s] %d/%d  ok=%d fail=%d  eta=%s ", bar, completed, p.total, succeeded, failed, eta)
+}
+
+// finish clears the progress line so subsequent output starts clean
+func (p *progressBar) finish() {
+	fmt.Print("\r" + strings.Repeat(" ", progressBarWidth+60) + "\r")
+}
+
+// estimateETA projects remaining time from the average pace so far
+func estimateETA(startTime time.Time, completed, total int) string {
+	if completed == 0 || completed >= total {
+		return "0s"
+	}
+	elapsed := time.Since(startTime)
+	perItem := elapsed / time.Duration(completed)
+	remaining := perItem * time.Duration(total-completed)
+	return remaining.Round(time.Second).String()
+}
+
+// formatResultLine renders one completed GameResult as a colorized text line,
+// used for the non-TTY text format (and as a fallback when TTY detection fails)
+func formatResultLine(result GameResult, completedCount, totalGames int, verbose bool) string {
+	if result.Success {
+		mark := successColor.Sprint("✓")
+		return fmt.Sprintf("[%d/%d] %s... %s Found (ID: %d)", completedCount, totalGames, result.GameName, mark, result.GameID)
+	}
+
+	mark := failureColor.Sprint("✗")
+	errorMessage := "Not found"
+	if verbose && result.Error != nil {
+		errorMessage = result.Error.Error()
+	}
+	return fmt.Sprintf("[%d/%d] %s... %s %s", completedCount, totalGames, result.GameName, mark, errorMessage)
+}
+
+// ndjsonResult is the JSON shape for one completed GameResult, used both for
+// --output-format ndjson (one per line) and as each entry of --output-format json's
+// "results" array. GameResult.Error is an error interface, which encoding/json can't
+// marshal usefully on its own, so this mirrors its exported fields as plain JSON types.
+type ndjsonResult struct {
+	Name    string `json:"name"`
+	ID      int    `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+func newNDJSONResult(result GameResult) ndjsonResult {
+	record := ndjsonResult{Name: result.GameName, ID: result.GameID, Success: result.Success}
+	if result.Error != nil {
+		record.Error = result.Error.Error()
+	}
+	return record
+}
+
+// MarshalJSON renders a GameResult the same way newNDJSONResult does, so
+// --output-format json and --output-format ndjson agree on per-result shape.
+func (r GameResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newNDJSONResult(r))
+}
+
+// writeNDJSONLine writes a single compact JSON object followed by a newline
+func writeNDJSONLine(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.Encode(v)
+}
+
+// runSummary is the machine-readable summary emitted at the end of a run in
+// json/ndjson mode, and printed as text otherwise
+type runSummary struct {
+	Success      int      `json:"success"`
+	Failed       int      `json:"failed"`
+	Output       string   `json:"output"`
+	FailedGames  []string `json:"failed_games,omitempty"`
+	Retries      int      `json:"retries,omitempty"`
+	ThrottledFor string   `json:"throttled_for,omitempty"`
+}
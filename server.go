@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LookupResponse is the JSON shape returned for a single name -> appid lookup
+type LookupResponse struct {
+	Name    string            `json:"name"`
+	ID      int               `json:"id"`
+	Matches []SteamSearchItem `json:"matches,omitempty"`
+}
+
+// batchLookupRequest is the JSON body accepted by POST /lookup
+type batchLookupRequest struct {
+	Names []string `json:"names"`
+}
+
+const (
+	// maxBatchNames bounds how many names a single POST /lookup can request, so a
+	// client can't force the server to fan out an unbounded number of lookups at once
+	maxBatchNames = 200
+	// maxBatchBodyBytes bounds the request body size read before maxBatchNames is
+	// even checked
+	maxBatchBodyBytes = 1 << 20 // 1 MiB
+)
+
+// serverMetrics tracks the counters and latencies exposed on /metrics
+type serverMetrics struct {
+	cacheHits uint64
+	apiCalls  uint64
+	failures  uint64
+	retries   uint64
+
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+// maxTrackedLatencies bounds memory use for the /metrics percentile calculation
+const maxTrackedLatencies = 10000
+
+func (m *serverMetrics) recordLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies = append(m.latencies, d)
+	if len(m.latencies) > maxTrackedLatencies {
+		m.latencies = m.latencies[len(m.latencies)-maxTrackedLatencies:]
+	}
+}
+
+// percentile returns the p-th percentile latency (p in [0, 1])
+func (m *serverMetrics) percentile(p float64) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), m.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(p * float64(len(sorted)-1))
+	return sorted[index]
+}
+
+// writePrometheus renders the counters and latency percentiles in Prometheus text format
+func (m *serverMetrics) writePrometheus(w http.ResponseWriter) {
+	fmt.Fprintf(w, "# HELP idsteamed_cache_hits_total Lookups served from the on-disk .steam cache\n")
+	fmt.Fprintf(w, "# TYPE idsteamed_cache_hits_total counter\n")
+	fmt.Fprintf(w, "idsteamed_cache_hits_total %d\n", atomic.LoadUint64(&m.cacheHits))
+
+	fmt.Fprintf(w, "# HELP idsteamed_api_calls_total Lookups that queried the Steam Store API\n")
+	fmt.Fprintf(w, "# TYPE idsteamed_api_calls_total counter\n")
+	fmt.Fprintf(w, "idsteamed_api_calls_total %d\n", atomic.LoadUint64(&m.apiCalls))
+
+	fmt.Fprintf(w, "# HELP idsteamed_failures_total Lookups that failed to resolve an appid\n")
+	fmt.Fprintf(w, "# TYPE idsteamed_failures_total counter\n")
+	fmt.Fprintf(w, "idsteamed_failures_total %d\n", atomic.LoadUint64(&m.failures))
+
+	fmt.Fprintf(w, "# HELP idsteamed_retries_total Retries performed against the Steam Store API\n")
+	fmt.Fprintf(w, "# TYPE idsteamed_retries_total counter\n")
+	fmt.Fprintf(w, "idsteamed_retries_total %d\n", atomic.LoadUint64(&m.retries))
+
+	fmt.Fprintf(w, "# HELP idsteamed_lookup_latency_seconds Lookup latency percentiles\n")
+	fmt.Fprintf(w, "# TYPE idsteamed_lookup_latency_seconds summary\n")
+	fmt.Fprintf(w, "idsteamed_lookup_latency_seconds{quantile=\"0.5\"} %f\n", m.percentile(0.5).Seconds())
+	fmt.Fprintf(w, "idsteamed_lookup_latency_seconds{quantile=\"0.95\"} %f\n", m.percentile(0.95).Seconds())
+}
+
+// lookupServer exposes the existing lookup pipeline (worker pool, rate limiter, and
+// on-disk .steam cache) over HTTP so other tools can resolve names without spawning
+// a new process per lookup.
+type lookupServer struct {
+	outputDir string
+	opts      lookupOptions
+	verbose   bool
+	metrics   *serverMetrics
+	workers   chan struct{} // bounds concurrent resolve() calls, same cap as --workers
+}
+
+// resolve looks up a single game name, checking the persistent SQLite cache (if
+// configured) and the on-disk .steam cache before falling back to the Steam Store
+// API, and writes a fresh result back to whichever caches are configured.
+func (s *lookupServer) resolve(ctx context.Context, gameName string) (LookupResponse, error) {
+	sanitizedFilename := sanitizeFilename(gameName)
+	steamFilePath := filepath.Join(s.outputDir, sanitizedFilename+".steam")
+
+	if s.opts.cache != nil && !s.opts.refresh {
+		if entry, found, err := s.opts.cache.Lookup(gameName); err != nil {
+			if s.verbose {
+				fmt.Fprintf(os.Stderr, "  [WARN] Cache lookup failed for %s: %v\n", gameName, err)
+			}
+		} else if found && !entry.Expired {
+			atomic.AddUint64(&s.metrics.cacheHits, 1)
+			if entry.Error != "" {
+				return LookupResponse{}, fmt.Errorf("%s", entry.Error)
+			}
+			return LookupResponse{Name: gameName, ID: entry.AppID}, nil
+		}
+	}
+
+	if cachedContent, err := os.ReadFile(steamFilePath); err == nil {
+		var cachedID int
+		if _, err := fmt.Sscanf(string(cachedContent), "%d", &cachedID); err == nil {
+			atomic.AddUint64(&s.metrics.cacheHits, 1)
+			return LookupResponse{Name: gameName, ID: cachedID}, nil
+		}
+	}
+
+	atomic.AddUint64(&s.metrics.apiCalls, 1)
+	items, retryCount, _, err := querySteamSearchItems(ctx, gameName, s.opts.limiter, s.opts.maxRetries, s.verbose)
+	atomic.AddUint64(&s.metrics.retries, uint64(retryCount))
+	if err != nil {
+		atomic.AddUint64(&s.metrics.failures, 1)
+		s.storeCacheResult(gameName, 0, err)
+		return LookupResponse{}, err
+	}
+
+	gameID, err := selectSteamGameID(gameName, items, false, s.opts.scoreThreshold, nil, s.verbose)
+	if err != nil {
+		atomic.AddUint64(&s.metrics.failures, 1)
+		s.storeCacheResult(gameName, 0, err)
+		return LookupResponse{}, err
+	}
+
+	if err := os.WriteFile(steamFilePath, []byte(fmt.Sprintf("%d", gameID)), fileMode); err != nil && s.verbose {
+		fmt.Fprintf(os.Stderr, "  [WARN] Could not write cache file %s: %v\n", steamFilePath, err)
+	}
+	s.storeCacheResult(gameName, gameID, nil)
+
+	matches := items
+	if len(matches) > maxVerboseResults {
+		matches = matches[:maxVerboseResults]
+	}
+	return LookupResponse{Name: gameName, ID: gameID, Matches: matches}, nil
+}
+
+// storeCacheResult writes a resolved or failed lookup back to the SQLite cache, if
+// one is configured, the same way processSingleGame does for the batch CLI mode.
+func (s *lookupServer) storeCacheResult(gameName string, gameID int, lookupErr error) {
+	if s.opts.cache == nil {
+		return
+	}
+
+	ttl := defaultCacheMissTTL
+	if lookupErr == nil {
+		ttl = s.opts.cacheHitTTL
+		if ttl <= 0 {
+			ttl = defaultCacheHitTTL
+		}
+	}
+
+	if cacheErr := s.opts.cache.Store(gameName, gameID, lookupErr, ttl); cacheErr != nil {
+		fmt.Fprintf(os.Stderr, "  [WARN] Could not cache lookup for %s: %v\n", gameName, cacheErr)
+	}
+}
+
+func (s *lookupServer) handleLookup(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		gameName := r.URL.Query().Get("name")
+		if gameName == "" {
+			http.Error(w, `{"error":"missing required query parameter 'name'"}`, http.StatusBadRequest)
+			return
+		}
+
+		s.workers <- struct{}{}
+		defer func() { <-s.workers }()
+
+		startTime := time.Now()
+		response, err := s.resolve(r.Context(), gameName)
+		s.metrics.recordLatency(time.Since(startTime))
+		if err != nil {
+			http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+
+		var request batchLookupRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, `{"error":"invalid JSON body"}`, http.StatusBadRequest)
+			return
+		}
+		if len(request.Names) > maxBatchNames {
+			http.Error(w, fmt.Sprintf(`{"error":"too many names: %d (max %d)"}`, len(request.Names), maxBatchNames), http.StatusBadRequest)
+			return
+		}
+
+		// Each lookup is dispatched to the same bounded worker pool the GET path
+		// and the batch CLI mode use, so a large batch queues rather than forking
+		// unbounded goroutines.
+		responses := make([]LookupResponse, len(request.Names))
+		var waitGroup sync.WaitGroup
+		for i, gameName := range request.Names {
+			waitGroup.Add(1)
+			go func(index int, name string) {
+				defer waitGroup.Done()
+				s.workers <- struct{}{}
+				defer func() { <-s.workers }()
+
+				startTime := time.Now()
+				response, err := s.resolve(r.Context(), name)
+				s.metrics.recordLatency(time.Since(startTime))
+				if err != nil {
+					response = LookupResponse{Name: name}
+				}
+				responses[index] = response
+			}(i, gameName)
+		}
+		waitGroup.Wait()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Results []LookupResponse `json:"results"`
+		}{Results: responses})
+
+	default:
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *lookupServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"status":"ok"}`)
+}
+
+func (s *lookupServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.metrics.writePrometheus(w)
+}
+
+// runServer starts the HTTP lookup daemon on addr and blocks until it exits.
+// workerCount bounds how many lookups (single or batch) run concurrently, the same
+// role --workers plays for the batch CLI mode.
+func runServer(addr string, outputDir string, opts lookupOptions, verbose bool, workerCount int) error {
+	server := &lookupServer{
+		outputDir: outputDir,
+		opts:      opts,
+		verbose:   verbose,
+		metrics:   &serverMetrics{},
+		workers:   make(chan struct{}, workerCount),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lookup", server.handleLookup)
+	mux.HandleFunc("/healthz", server.handleHealthz)
+	mux.HandleFunc("/metrics", server.handleMetrics)
+
+	fmt.Printf("Listening on %s\n", addr)
+	fmt.Printf("  GET  http://%s/lookup?name=Half-Life+2\n", addr)
+	fmt.Printf("  POST http://%s/lookup\n", addr)
+	fmt.Printf("  GET  http://%s/healthz\n", addr)
+	fmt.Printf("  GET  http://%s/metrics\n", addr)
+
+	return http.ListenAndServe(addr, mux)
+}
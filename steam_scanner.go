@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// InstalledGame represents a game discovered in a local Steam library via its
+// appmanifest, already carrying the appid so no Steam Store lookup is needed.
+type InstalledGame struct {
+	AppID int
+	Name  string
+}
+
+// vdfKeyValuePattern matches a "key" "value" pair in Valve's VDF format, at any
+// nesting depth. Values may contain escaped backslashes (Windows paths).
+var vdfKeyValuePattern = regexp.MustCompile(`"([A-Za-z0-9_]+)"\s*"((?:[^"\\]|\\.)*)"`)
+
+// parseVDFPairs extracts every "key" "value" pair from a VDF document, in file
+// order. VDF keys can repeat (e.g. "path" once per library folder), so pairs
+// are returned as a slice rather than a map.
+func parseVDFPairs(content []byte) [][2]string {
+	matches := vdfKeyValuePattern.FindAllSubmatch(content, -1)
+	pairs := make([][2]string, 0, len(matches))
+	for _, match := range matches {
+		key := string(match[1])
+		value := unescapeVDFString(string(match[2]))
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs
+}
+
+// unescapeVDFString undoes VDF's backslash escaping (used for Windows paths
+// like "C:\\Program Files (x86)\\Steam").
+func unescapeVDFString(value string) string {
+	return strings.ReplaceAll(value, `\\`, `\`)
+}
+
+// detectSteamDir tries the default Steam install location for the current OS
+// and returns the first one that exists.
+func detectSteamDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	var candidates []string
+	switch runtime.GOOS {
+	case "linux":
+		candidates = []string{
+			filepath.Join(homeDir, ".steam", "steam"),
+			filepath.Join(homeDir, ".local", "share", "Steam"),
+		}
+	case "darwin":
+		candidates = []string{
+			filepath.Join(homeDir, "Library", "Application Support", "Steam"),
+		}
+	case "windows":
+		candidates = []string{
+			`C:\Program Files (x86)\Steam`,
+		}
+	default:
+		return "", fmt.Errorf("steam directory auto-detection not supported on %s", runtime.GOOS)
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not auto-detect Steam directory, tried: %s", strings.Join(candidates, ", "))
+}
+
+// parseLibraryFolders reads steamapps/libraryfolders.vdf under steamDir and
+// returns every library path it declares, including steamDir itself. Modern
+// Steam installs list the primary library's own path as library "0", so
+// steamDir is only added if it wasn't already among the parsed paths.
+func parseLibraryFolders(steamDir string) ([]string, error) {
+	libraryFoldersPath := filepath.Join(steamDir, "steamapps", "libraryfolders.vdf")
+	content, err := os.ReadFile(libraryFoldersPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No additional libraries declared - the primary one is all we have
+			return []string{steamDir}, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", libraryFoldersPath, err)
+	}
+
+	seen := map[string]bool{}
+	var libraryPaths []string
+	addLibraryPath := func(path string) {
+		cleaned := filepath.Clean(path)
+		if seen[cleaned] {
+			return
+		}
+		seen[cleaned] = true
+		libraryPaths = append(libraryPaths, cleaned)
+	}
+
+	addLibraryPath(steamDir)
+	for _, pair := range parseVDFPairs(content) {
+		if pair[0] == "path" {
+			addLibraryPath(pair[1])
+		}
+	}
+	return libraryPaths, nil
+}
+
+// parseAppManifest reads a single steamapps/appmanifest_*.acf file and
+// extracts the appid and display name Steam recorded for that install.
+func parseAppManifest(manifestPath string) (InstalledGame, error) {
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return InstalledGame{}, fmt.Errorf("error reading %s: %w", manifestPath, err)
+	}
+
+	var appIDString, name string
+	for _, pair := range parseVDFPairs(content) {
+		switch pair[0] {
+		case "appid":
+			appIDString = pair[1]
+		case "name":
+			name = pair[1]
+		}
+	}
+
+	if appIDString == "" || name == "" {
+		return InstalledGame{}, fmt.Errorf("%s is missing appid or name", manifestPath)
+	}
+
+	appID, err := strconv.Atoi(appIDString)
+	if err != nil {
+		return InstalledGame{}, fmt.Errorf("%s has non-numeric appid %q: %w", manifestPath, appIDString, err)
+	}
+
+	return InstalledGame{AppID: appID, Name: name}, nil
+}
+
+// scanInstalledGames walks every library declared under steamDir and parses
+// each appmanifest it finds, returning the full set of locally installed games.
+func scanInstalledGames(steamDir string, verbose bool) ([]InstalledGame, error) {
+	libraryPaths, err := parseLibraryFolders(steamDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var installedGames []InstalledGame
+	for _, libraryPath := range libraryPaths {
+		manifestPattern := filepath.Join(libraryPath, "steamapps", "appmanifest_*.acf")
+		manifestPaths, err := filepath.Glob(manifestPattern)
+		if err != nil {
+			return nil, fmt.Errorf("error globbing %s: %w", manifestPattern, err)
+		}
+
+		for _, manifestPath := range manifestPaths {
+			game, err := parseAppManifest(manifestPath)
+			if err != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "  [WARN] Skipping %s: %v\n", manifestPath, err)
+				}
+				continue
+			}
+			installedGames = append(installedGames, game)
+		}
+	}
+
+	return installedGames, nil
+}
+
+// normalizeGameName reduces a game name to a form suitable for matching a
+// text-file entry against an installed manifest's name, regardless of case.
+func normalizeGameName(gameName string) string {
+	return strings.ToLower(strings.TrimSpace(gameName))
+}